@@ -0,0 +1,99 @@
+package gobusterdns
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/OJ/gobuster/libgobuster"
+	"github.com/miekg/dns"
+)
+
+// tryAXFR enumerates the NS records for the target domain and attempts a
+// zone transfer against each of them. Any nameserver that honours the
+// transfer can turn an otherwise multi-hour brute-force into a formality:
+// every record it hands back is queued up to ride along on the first Run
+// call, ahead of the wordlist.
+func (d *GobusterDNS) tryAXFR() {
+	if !d.options.TryAXFR {
+		return
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(d.options.Domain), dns.TypeNS)
+	in, err := d.resolvers.exchange(m)
+	if err != nil {
+		return
+	}
+
+	for _, rr := range in.Answer {
+		ns, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+
+		results := d.axfrAttempt(ns.Ns)
+		if len(results) == 0 {
+			continue
+		}
+
+		log.Printf("[-] Zone transfer succeeded against %s (%d names)", ns.Ns, len(results))
+		d.queuePending(results)
+		if !d.options.AXFRContinue {
+			d.skipWordlist = true
+			return
+		}
+	}
+}
+
+// axfrAttempt performs a single AXFR against server (a nameserver host
+// name) for the target domain, returning one libgobuster.Result per owner
+// name received (carrying all of that name's records), or nil if the
+// transfer was refused or failed.
+func (d *GobusterDNS) axfrAttempt(server string) []libgobuster.Result {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(d.options.Domain))
+
+	tr := &dns.Transfer{
+		DialTimeout: d.resolvers.timeout,
+		ReadTimeout: d.resolvers.timeout,
+	}
+
+	envelopes, err := tr.In(m, fmt.Sprintf("%s:53", server))
+	if err != nil {
+		return nil
+	}
+
+	var order []string
+	entriesByName := make(map[string][]recordTypeEntry)
+	for env := range envelopes {
+		if env.Error != nil {
+			return nil
+		}
+		for _, rr := range env.RR {
+			value := rrValue(rr)
+			if value == "" {
+				continue
+			}
+			name := rr.Header().Name
+			if _, seen := entriesByName[name]; !seen {
+				order = append(order, name)
+			}
+			entriesByName[name] = append(entriesByName[name], recordTypeEntry{
+				Type:  dns.TypeToString[rr.Header().Rrtype],
+				Value: value,
+			})
+		}
+	}
+
+	// Same JSON entry encoding queryRecordTypes uses, so ResultToString
+	// renders AXFR hits through the same aligned tabwriter as any other
+	// record-type result instead of a raw one-line dump.
+	results := make([]libgobuster.Result, 0, len(order))
+	for _, name := range order {
+		results = append(results, libgobuster.Result{
+			Entity: name,
+			Extra:  formatRecordEntries(entriesByName[name]),
+		})
+	}
+	return results
+}
@@ -5,20 +5,32 @@ import (
 	"bytes"
 	"fmt"
 	"log"
-	"net"
 	"strings"
+	"sync"
 	"text/tabwriter"
 
 	"github.com/OJ/gobuster/libgobuster"
-	"github.com/google/uuid"
+	"github.com/miekg/dns"
 )
 
 // GobusterDNS is the main type to implement the interface
 type GobusterDNS struct {
-	globalopts  *libgobuster.Options
-	options     *OptionsDNS
-	isWildcard  bool
-	wildcardIps libgobuster.StringSet
+	globalopts           *libgobuster.Options
+	options              *OptionsDNS
+	isWildcard           bool
+	wildcardFingerprints map[string]struct{}
+	resolvers            *resolverPool
+	enqueuer             Enqueuer
+	depth                int
+	skipWordlist         bool
+
+	// pendingMu guards pendingResults: AXFR (from PreRun) and recursion
+	// (from a goroutine maybeRecurse spins up per hit) both feed it,
+	// while Run - called concurrently by libgobuster's worker pool across
+	// Options.Threads goroutines against this single shared instance -
+	// drains it on every call.
+	pendingMu      sync.Mutex
+	pendingResults []libgobuster.Result
 }
 
 // NewGobusterDNS creates a new initialized GobusterDNS
@@ -31,32 +43,35 @@ func NewGobusterDNS(globalopts *libgobuster.Options, opts *OptionsDNS) (*Gobuste
 		return nil, fmt.Errorf("please provide valid plugin options")
 	}
 
+	pool, err := newResolverPool(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating resolver pool: %w", err)
+	}
+
 	g := GobusterDNS{
-		options:     opts,
-		globalopts:  globalopts,
-		wildcardIps: libgobuster.NewStringSet(),
+		options:              opts,
+		globalopts:           globalopts,
+		wildcardFingerprints: make(map[string]struct{}),
+		resolvers:            pool,
 	}
 	return &g, nil
 }
 
 // PreRun is the pre run implementation of gobusterdns
 func (d *GobusterDNS) PreRun() error {
-	// Resolve a subdomain sthat probably shouldn't exist
-	guid := uuid.New()
-	wildcardIps, err := dnsLookup(fmt.Sprintf("%s.%s", guid, d.options.Domain))
-	if err == nil {
-		d.isWildcard = true
-		d.wildcardIps.AddRange(wildcardIps)
-		log.Printf("[-] Wildcard DNS found. IP address(es): %s", d.wildcardIps.Stringify())
-		if !d.options.WildcardForced {
-			return fmt.Errorf("To force processing of Wildcard DNS, specify the '--wildcard' switch.")
-		}
+	if err := d.validateResolvers(); err != nil {
+		return err
 	}
 
+	if err := d.detectWildcard(); err != nil {
+		return err
+	}
+
+	d.tryAXFR()
+
 	if !d.globalopts.Quiet {
 		// Provide a warning if the base domain doesn't resolve (in case of typo)
-		_, err = dnsLookup(d.options.Domain)
-		if err != nil {
+		if _, err := d.dnsLookup(d.options.Domain); err != nil {
 			// Not an error, just a warning. Eg. `yp.to` doesn't resolve, but `cr.py.to` does!
 			log.Printf("[-] Unable to validate base domain: %s", d.options.Domain)
 		}
@@ -65,26 +80,98 @@ func (d *GobusterDNS) PreRun() error {
 	return nil
 }
 
+// validateResolvers sends a test query to every configured resolver and
+// drops any that don't answer, so a single dead resolver in the pool
+// doesn't silently poison every lookup with retries.
+func (d *GobusterDNS) validateResolvers() error {
+	configured := d.resolvers.servers
+
+	alive := d.resolvers.probe(d.options.Domain)
+	if len(alive) == 0 {
+		// the base domain itself might not resolve, fall back to a
+		// well known name just to check the resolvers are reachable
+		alive = d.resolvers.probe("www.google.com")
+	}
+
+	for _, server := range configured {
+		found := false
+		for _, a := range alive {
+			if a == server {
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Printf("[-] Resolver %s did not respond, dropping it from the pool", server)
+		}
+	}
+
+	if len(alive) == 0 {
+		return fmt.Errorf("none of the configured resolvers responded")
+	}
+
+	d.resolvers.setServers(alive)
+	return nil
+}
+
+// queuePending appends results to pendingResults under lock. Safe to call
+// from any goroutine.
+func (d *GobusterDNS) queuePending(results []libgobuster.Result) {
+	if len(results) == 0 {
+		return
+	}
+	d.pendingMu.Lock()
+	d.pendingResults = append(d.pendingResults, results...)
+	d.pendingMu.Unlock()
+}
+
+// drainPending atomically takes ownership of the current pendingResults,
+// leaving the field empty for the next caller.
+func (d *GobusterDNS) drainPending() []libgobuster.Result {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+	if len(d.pendingResults) == 0 {
+		return nil
+	}
+	drained := d.pendingResults
+	d.pendingResults = nil
+	return drained
+}
+
 // Run is the process implementation of gobusterdns
 func (d *GobusterDNS) Run(word string) ([]libgobuster.Result, error) {
+	queuedRet := d.drainPending()
+	if d.skipWordlist {
+		return queuedRet, nil
+	}
+
 	subdomain := fmt.Sprintf("%s.%s", word, d.options.Domain)
-	ips, err := dnsLookup(subdomain)
-	var ret []libgobuster.Result
+
+	if d.isWildcard {
+		if fp, err := d.fingerprintChain(subdomain); err == nil && d.isWildcardResponse(fp) {
+			return queuedRet, nil
+		}
+	}
+
+	entries, err := d.queryRecordTypes(subdomain)
+	ret := queuedRet
 	if err == nil {
-		if !d.isWildcard || !d.wildcardIps.ContainsAny(ips) {
-			result := libgobuster.Result{
-				Entity: subdomain,
+		result := libgobuster.Result{
+			Entity: subdomain,
+			Extra:  formatRecordEntries(entries),
+		}
+		if result.Extra == "" && d.options.ShowCNAME {
+			if cname, err := d.dnsLookupCname(subdomain); err == nil {
+				result.Extra = cname
 			}
-			if d.options.ShowIPs {
+		}
+		if result.Extra == "" && d.options.ShowIPs {
+			if ips, err := d.dnsLookup(subdomain); err == nil {
 				result.Extra = strings.Join(ips, ", ")
-			} else if d.options.ShowCNAME {
-				cname, err := dnsLookupCname(subdomain)
-				if err == nil {
-					result.Extra = cname
-				}
 			}
-			ret = append(ret, result)
 		}
+		ret = append(ret, result)
+		d.maybeRecurse(subdomain)
 	} else if d.globalopts.Verbose {
 		ret = append(ret, libgobuster.Result{
 			Entity: subdomain,
@@ -94,6 +181,46 @@ func (d *GobusterDNS) Run(word string) ([]libgobuster.Result, error) {
 	return ret, nil
 }
 
+// queryRecordTypes queries every configured record type for subdomain and
+// folds the answers into a flat list of entries. It returns an error if no
+// query returned an allowed response code.
+func (d *GobusterDNS) queryRecordTypes(subdomain string) ([]recordTypeEntry, error) {
+	var entries []recordTypeEntry
+	found := false
+
+	for _, rt := range d.options.recordTypesOrDefault() {
+		qtype, ok := dnsTypeFromString(rt)
+		if !ok {
+			log.Printf("[-] Unknown record type %q, skipping", rt)
+			continue
+		}
+
+		m := new(dns.Msg)
+		m.SetQuestion(dns.Fqdn(subdomain), qtype)
+		in, err := d.resolvers.exchange(m)
+		if err != nil {
+			continue
+		}
+
+		if d.options.responseCodeAllowed(in.Rcode) {
+			found = true
+		}
+
+		for _, rr := range in.Answer {
+			value := rrValue(rr)
+			if value == "" {
+				continue
+			}
+			entries = append(entries, recordTypeEntry{Type: strings.ToUpper(rt), Value: value})
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no matching records found for %s", subdomain)
+	}
+	return entries, nil
+}
+
 // ResultToString is the to string implementation of gobusterdns
 func (d *GobusterDNS) ResultToString(r *libgobuster.Result) (*string, error) {
 	buf := &bytes.Buffer{}
@@ -102,16 +229,29 @@ func (d *GobusterDNS) ResultToString(r *libgobuster.Result) (*string, error) {
 		if _, err := fmt.Fprintf(buf, "Missing: %s\n", r.Entity); err != nil {
 			return nil, err
 		}
-	} else if d.options.ShowIPs {
-		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Extra); err != nil {
-			return nil, err
+		s := buf.String()
+		return &s, nil
+	}
+
+	if _, err := fmt.Fprintf(buf, "Found: %s\n", r.Entity); err != nil {
+		return nil, err
+	}
+
+	// When the lookup produced record entries, r.Extra holds a
+	// JSON-encoded []recordTypeEntry. Decode it and align it as a little
+	// table so a dozen TXT/MX records don't turn into a wall of text.
+	if entries, ok := decodeRecordEntries(r.Extra); ok {
+		tw := tabwriter.NewWriter(buf, 0, 5, 1, ' ', 0)
+		for _, e := range entries {
+			if _, err := fmt.Fprintf(tw, "  %s:\t%s\n", e.Type, e.Value); err != nil {
+				return nil, err
+			}
 		}
-	} else if d.options.ShowCNAME {
-		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Extra); err != nil {
+		if err := tw.Flush(); err != nil {
 			return nil, err
 		}
-	} else {
-		if _, err := fmt.Fprintf(buf, "Found: %s\n", r.Entity); err != nil {
+	} else if r.Extra != "" {
+		if _, err := fmt.Fprintf(buf, "  %s\n", r.Extra); err != nil {
 			return nil, err
 		}
 	}
@@ -176,10 +316,43 @@ func (d *GobusterDNS) GetConfigString() (string, error) {
 	return strings.TrimSpace(buffer.String()), nil
 }
 
-func dnsLookup(domain string) ([]string, error) {
-	return net.LookupHost(domain)
+// dnsLookup resolves the A/AAAA records for domain via the resolver pool.
+func (d *GobusterDNS) dnsLookup(domain string) ([]string, error) {
+	var ips []string
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		m := new(dns.Msg)
+		m.SetQuestion(dns.Fqdn(domain), qtype)
+		in, err := d.resolvers.exchange(m)
+		if err != nil {
+			continue
+		}
+		for _, rr := range in.Answer {
+			switch a := rr.(type) {
+			case *dns.A:
+				ips = append(ips, a.A.String())
+			case *dns.AAAA:
+				ips = append(ips, a.AAAA.String())
+			}
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no A/AAAA records found for %s", domain)
+	}
+	return ips, nil
 }
 
-func dnsLookupCname(domain string) (string, error) {
-	return net.LookupCNAME(domain)
+// dnsLookupCname resolves the CNAME record for domain via the resolver pool.
+func (d *GobusterDNS) dnsLookupCname(domain string) (string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), dns.TypeCNAME)
+	in, err := d.resolvers.exchange(m)
+	if err != nil {
+		return "", err
+	}
+	for _, rr := range in.Answer {
+		if cname, ok := rr.(*dns.CNAME); ok {
+			return cname.Target, nil
+		}
+	}
+	return "", fmt.Errorf("no CNAME record found for %s", domain)
 }
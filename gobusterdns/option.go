@@ -0,0 +1,66 @@
+package gobusterdns
+
+import "time"
+
+// OptionsDNS is the struct to hold all options for this plugin
+type OptionsDNS struct {
+	Domain         string
+	ShowIPs        bool
+	ShowCNAME      bool
+	WildcardForced bool
+	Resolvers      []string
+	// QueriesPerSecond caps the global query rate across the whole
+	// resolver pool. <= 0 means unlimited.
+	QueriesPerSecond int
+	// Retries is how many times a query is retried against another
+	// resolver in the pool after a SERVFAIL or timeout. <= 0 uses the
+	// default of 2.
+	Retries int
+	// Timeout is the per-query timeout used against a single resolver
+	// before it's considered failed for that attempt. <= 0 uses the
+	// default of 2 seconds.
+	Timeout time.Duration
+	// RecordTypes is the set of record types (A, AAAA, CNAME, NS, MX,
+	// TXT, SOA, PTR) queried for every candidate subdomain. Defaults to
+	// just A when unset.
+	RecordTypes []string
+	// ResponseCodes lists the rcodes (NOERROR, NXDOMAIN, REFUSED, ...)
+	// that count as a "found" result. Defaults to just NOERROR.
+	ResponseCodes []string
+	// WildcardProbes is how many random subdomains are resolved during
+	// PreRun to fingerprint wildcard DNS responses. Defaults to 5.
+	WildcardProbes int
+	// DoH switches the resolver pool to DNS-over-HTTPS (RFC 8484).
+	// Resolvers must then be full URLs, e.g. https://cloudflare-dns.com/dns-query.
+	//
+	// HTTP/3 is not supported: net/http has no native QUIC round-tripper,
+	// so a DoHHTTP3 toggle here would silently do nothing but serve over
+	// h2/1.1 anyway. Revisit once a QUIC-capable transport is vendored.
+	DoH bool
+	// DoT switches the resolver pool to DNS-over-TLS (RFC 7858).
+	// Resolvers must then be host:port (port 853 is typical), optionally
+	// prefixed with tls://.
+	DoT bool
+	// Recursive enables queuing a freshly discovered subdomain back in
+	// as a new base domain for another brute-force pass.
+	Recursive bool
+	// RecursionDepth caps how many levels deep recursion is allowed to
+	// go. Defaults to 1 (recurse once into each hit).
+	RecursionDepth int
+	// RecursionWordlist is the wordlist used for recursive passes. Falls
+	// back to the main wordlist when empty.
+	RecursionWordlist string
+	// TryAXFR attempts a zone transfer against the domain's nameservers
+	// during PreRun before falling back to the wordlist. The --try-axfr
+	// flag defaults this to true.
+	TryAXFR bool
+	// AXFRContinue keeps the wordlist-driven Run loop going even after a
+	// successful zone transfer, instead of treating it as the complete
+	// answer.
+	AXFRContinue bool
+}
+
+// NewOptionsDNS returns a new initialized OptionsDNS
+func NewOptionsDNS() *OptionsDNS {
+	return &OptionsDNS{}
+}
@@ -0,0 +1,123 @@
+package gobusterdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// recordTypeEntry pairs a record type string with the value gobusterdns
+// rendered from the matching RR, e.g. {"A", "93.184.216.34"}. It's kept
+// structured all the way through to ResultToString (via a JSON-encoded
+// libgobuster.Result.Extra) instead of being joined with a delimiter,
+// because record values - TXT in particular - can themselves legitimately
+// contain "; " or "=".
+type recordTypeEntry struct {
+	Type  string
+	Value string
+}
+
+// dnsTypeFromString maps the user facing record type strings accepted by
+// --record-types to the matching miekg/dns query type constant.
+func dnsTypeFromString(s string) (uint16, bool) {
+	switch strings.ToUpper(s) {
+	case "A":
+		return dns.TypeA, true
+	case "AAAA":
+		return dns.TypeAAAA, true
+	case "CNAME":
+		return dns.TypeCNAME, true
+	case "NS":
+		return dns.TypeNS, true
+	case "MX":
+		return dns.TypeMX, true
+	case "TXT":
+		return dns.TypeTXT, true
+	case "SOA":
+		return dns.TypeSOA, true
+	case "PTR":
+		return dns.TypePTR, true
+	default:
+		return 0, false
+	}
+}
+
+// rrValue renders the useful payload of an answer RR as a short string
+// suitable for the recordTypeEntry values stored in libgobuster.Result.Extra.
+func rrValue(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return v.Target
+	case *dns.NS:
+		return v.Ns
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", v.Preference, v.Mx)
+	case *dns.TXT:
+		return strings.Join(v.Txt, " ")
+	case *dns.SOA:
+		return fmt.Sprintf("%s %s", v.Ns, v.Mbox)
+	case *dns.PTR:
+		return v.Ptr
+	default:
+		return ""
+	}
+}
+
+// formatRecordEntries JSON-encodes entries for storing in
+// libgobuster.Result.Extra. ResultToString later decodes this to render an
+// aligned table. JSON properly escapes record values instead of relying on
+// a delimiter that record data could collide with.
+func formatRecordEntries(entries []recordTypeEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// decodeRecordEntries reverses formatRecordEntries. It returns ok=false for
+// any Extra value that isn't a JSON-encoded []recordTypeEntry, e.g. the
+// plain CNAME/IP strings ShowCNAME/ShowIPs still produce.
+func decodeRecordEntries(extra string) (entries []recordTypeEntry, ok bool) {
+	if err := json.Unmarshal([]byte(extra), &entries); err != nil {
+		return nil, false
+	}
+	return entries, len(entries) > 0
+}
+
+// responseCodeAllowed reports whether rcode is one of the response codes
+// the user configured as a "found" signal via --response-codes. With no
+// configuration only NOERROR counts, matching the original behaviour.
+func (o *OptionsDNS) responseCodeAllowed(rcode int) bool {
+	name, ok := dns.RcodeToString[rcode]
+	if !ok {
+		return false
+	}
+	if len(o.ResponseCodes) == 0 {
+		return rcode == dns.RcodeSuccess
+	}
+	for _, c := range o.ResponseCodes {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordTypesOrDefault returns the configured record types, defaulting to a
+// plain A lookup to preserve the original "does it resolve" behaviour.
+func (o *OptionsDNS) recordTypesOrDefault() []string {
+	if len(o.RecordTypes) == 0 {
+		return []string{"A"}
+	}
+	return o.RecordTypes
+}
@@ -0,0 +1,91 @@
+package gobusterdns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestDnsTypeFromString(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   uint16
+		wantOk bool
+	}{
+		{"A", dns.TypeA, true},
+		{"txt", dns.TypeTXT, true},
+		{"CnAmE", dns.TypeCNAME, true},
+		{"BOGUS", 0, false},
+	}
+
+	for _, tc := range tests {
+		got, ok := dnsTypeFromString(tc.in)
+		if ok != tc.wantOk || (ok && got != tc.want) {
+			t.Errorf("dnsTypeFromString(%q) = (%v, %v), want (%v, %v)", tc.in, got, ok, tc.want, tc.wantOk)
+		}
+	}
+}
+
+func TestRrValue(t *testing.T) {
+	a := &dns.A{A: net.ParseIP("93.184.216.34")}
+	if got := rrValue(a); got != "93.184.216.34" {
+		t.Errorf("rrValue(A) = %q, want %q", got, "93.184.216.34")
+	}
+
+	txt := &dns.TXT{Txt: []string{"v=spf1", "include:_spf.example.com ~all"}}
+	if got, want := rrValue(txt), "v=spf1 include:_spf.example.com ~all"; got != want {
+		t.Errorf("rrValue(TXT) = %q, want %q", got, want)
+	}
+}
+
+func TestResponseCodeAllowed(t *testing.T) {
+	o := &OptionsDNS{}
+	if !o.responseCodeAllowed(dns.RcodeSuccess) {
+		t.Error("default OptionsDNS should allow NOERROR")
+	}
+	if o.responseCodeAllowed(dns.RcodeNameError) {
+		t.Error("default OptionsDNS should not allow NXDOMAIN")
+	}
+
+	o.ResponseCodes = []string{"NXDOMAIN", "refused"}
+	if o.responseCodeAllowed(dns.RcodeSuccess) {
+		t.Error("configured ResponseCodes should no longer allow NOERROR")
+	}
+	if !o.responseCodeAllowed(dns.RcodeNameError) {
+		t.Error("configured ResponseCodes should allow NXDOMAIN")
+	}
+	if !o.responseCodeAllowed(dns.RcodeRefused) {
+		t.Error("configured ResponseCodes should allow REFUSED case-insensitively")
+	}
+}
+
+func TestFormatAndDecodeRecordEntriesRoundTrip(t *testing.T) {
+	entries := []recordTypeEntry{
+		{Type: "TXT", Value: `v=spf1; include:_spf.example.com ~all`},
+		{Type: "A", Value: "93.184.216.34"},
+	}
+
+	extra := formatRecordEntries(entries)
+
+	decoded, ok := decodeRecordEntries(extra)
+	if !ok {
+		t.Fatalf("decodeRecordEntries(%q) returned ok=false", extra)
+	}
+	if len(decoded) != len(entries) {
+		t.Fatalf("decodeRecordEntries returned %d entries, want %d", len(decoded), len(entries))
+	}
+	for i, e := range entries {
+		if decoded[i] != e {
+			t.Errorf("decoded[%d] = %+v, want %+v", i, decoded[i], e)
+		}
+	}
+}
+
+func TestDecodeRecordEntriesRejectsPlainStrings(t *testing.T) {
+	for _, s := range []string{"", "93.184.216.34", "A=93.184.216.34"} {
+		if _, ok := decodeRecordEntries(s); ok {
+			t.Errorf("decodeRecordEntries(%q) returned ok=true, want false", s)
+		}
+	}
+}
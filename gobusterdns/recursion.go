@@ -0,0 +1,120 @@
+package gobusterdns
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Enqueuer lets an external engine take over scheduling recursive
+// brute-force passes instead of GobusterDNS driving them inline. It's
+// optional: libgobuster.Runner (outside this package) is expected to
+// type-assert freshly constructed plugins against Enqueuer and call
+// SetEnqueuer before PreRun when it wants centralized control over the
+// work queue (e.g. to share a thread pool with the top level run); the
+// matching plugin-interface addition lives in the libgobuster package, not
+// here. With no Enqueuer wired up, GobusterDNS drives the nested pass
+// itself via recurseInline so recursion still works out of the box.
+type Enqueuer interface {
+	Enqueue(domain, wordlist string, depth int) error
+}
+
+// SetEnqueuer wires the callback used to schedule recursive brute-force
+// passes on an external engine.
+func (d *GobusterDNS) SetEnqueuer(e Enqueuer) {
+	d.enqueuer = e
+}
+
+// SetDepth records how many recursive hops deep this GobusterDNS instance
+// already is. Every nested instance recurseInline constructs has this set
+// explicitly, so depth tracking never has to be reconstructed from the
+// domain string (which can't distinguish a level-1 hit from a level-3 one).
+func (d *GobusterDNS) SetDepth(depth int) {
+	d.depth = depth
+}
+
+// maybeRecurse schedules a fresh brute-force pass against subdomain once a
+// hit is confirmed, provided recursion is enabled and the configured depth
+// hasn't been exhausted yet. An external Enqueuer takes priority when one
+// has been wired up; otherwise GobusterDNS runs the nested pass itself.
+func (d *GobusterDNS) maybeRecurse(subdomain string) {
+	if !d.options.Recursive {
+		return
+	}
+
+	maxDepth := d.options.RecursionDepth
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
+	if d.depth >= maxDepth {
+		return
+	}
+
+	wordlist := d.options.RecursionWordlist
+
+	if d.enqueuer != nil {
+		if err := d.enqueuer.Enqueue(subdomain, wordlist, d.depth+1); err != nil {
+			log.Printf("[-] Failed to queue recursive brute-force for %s: %v", subdomain, err)
+		}
+		return
+	}
+
+	// No engine-level scheduler is wired up, so there's no thread pool to
+	// hand this off to. Run it detached instead of blocking the worker
+	// goroutine that's in the middle of a Run() call for the rest of the
+	// (parent) wordlist - at the cost of recursive passes not counting
+	// against --threads the way the top-level run does.
+	go func() {
+		if err := d.recurseInline(subdomain, wordlist); err != nil {
+			log.Printf("[-] Recursive brute-force of %s failed: %v", subdomain, err)
+		}
+	}()
+}
+
+// recurseInline runs a full nested PreRun/Run pass against subdomain when no
+// external Enqueuer is available. Its results are queued (safe for
+// concurrent use, see queuePending) and ride along on the caller's next Run
+// call.
+func (d *GobusterDNS) recurseInline(subdomain, wordlist string) error {
+	if wordlist == "" {
+		wordlist = d.globalopts.Wordlist
+	}
+	if wordlist == "-" {
+		return fmt.Errorf("recursion requires a real wordlist file, stdin can't be re-read")
+	}
+
+	f, err := os.Open(wordlist)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	nestedOpts := *d.options
+	nestedOpts.Domain = subdomain
+	nested, err := NewGobusterDNS(d.globalopts, &nestedOpts)
+	if err != nil {
+		return err
+	}
+	nested.SetDepth(d.depth + 1)
+
+	// Wildcard detection must run again for the nested domain: inner
+	// zones can have their own wildcards distinct from the parent's.
+	if err := nested.PreRun(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := scanner.Text()
+		if word == "" {
+			continue
+		}
+		results, err := nested.Run(word)
+		if err != nil {
+			continue
+		}
+		d.queuePending(results)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,172 @@
+package gobusterdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/time/rate"
+)
+
+// defaultResolvers is used for plain UDP/TCP when the user doesn't
+// configure any resolvers of their own.
+var defaultResolvers = []string{
+	"8.8.8.8:53",
+	"1.1.1.1:53",
+}
+
+// defaultDoHResolvers is used for --doh when the user doesn't configure any
+// resolvers of their own. Plain defaultResolvers aren't valid DoH query
+// URLs, so DoH needs its own set.
+var defaultDoHResolvers = []string{
+	"https://cloudflare-dns.com/dns-query",
+	"https://dns.google/dns-query",
+}
+
+// defaultDoTResolvers is used for --dot when the user doesn't configure any
+// resolvers of their own. defaultResolvers point at port 53, not the 853
+// DoT listens on.
+var defaultDoTResolvers = []string{
+	"1.1.1.1:853",
+	"8.8.8.8:853",
+}
+
+// defaultResolverTimeout is the per-query timeout used against a single
+// resolver before it is considered failed for that attempt.
+const defaultResolverTimeout = 2 * time.Second
+
+// defaultResolverRetries is how many times a query is retried (against the
+// next resolver in the pool) before giving up.
+const defaultResolverRetries = 2
+
+// resolverPool round-robins DNS queries across a set of resolvers, retrying
+// on failure (SERVFAIL, timeout, ...) and rate limiting the overall query
+// rate across the whole pool. The actual wire format (plain UDP/TCP, DoT,
+// DoH) is delegated to a dnsTransport.
+type resolverPool struct {
+	transport dnsTransport
+	servers   []string
+	next      uint32
+	retries   int
+	timeout   time.Duration
+	limiter   *rate.Limiter
+}
+
+// newResolverPool builds a resolverPool configured per opts: plain UDP/TCP
+// by default, or DoH/DoT when requested. If opts.Resolvers is empty the
+// built in defaults for the chosen transport (defaultResolvers,
+// defaultDoHResolvers or defaultDoTResolvers) are used.
+func newResolverPool(opts *OptionsDNS) (*resolverPool, error) {
+	servers := opts.Resolvers
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultResolverTimeout
+	}
+	retries := opts.Retries
+	if retries <= 0 {
+		retries = defaultResolverRetries
+	}
+
+	var transport dnsTransport
+	switch {
+	case opts.DoH && opts.DoT:
+		return nil, fmt.Errorf("--doh and --dot are mutually exclusive")
+	case opts.DoH:
+		if len(servers) == 0 {
+			servers = defaultDoHResolvers
+		}
+		transport = newDoHTransport(timeout)
+	case opts.DoT:
+		if len(servers) == 0 {
+			servers = defaultDoTResolvers
+		}
+		transport = newClassicTransport(true, timeout)
+		for i, s := range servers {
+			servers[i] = strings.TrimPrefix(s, "tls://")
+		}
+	default:
+		if len(servers) == 0 {
+			servers = defaultResolvers
+		}
+		transport = newClassicTransport(false, timeout)
+	}
+
+	var limiter *rate.Limiter
+	if opts.QueriesPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.QueriesPerSecond), opts.QueriesPerSecond)
+	}
+
+	return &resolverPool{
+		transport: transport,
+		servers:   servers,
+		retries:   retries,
+		timeout:   timeout,
+		limiter:   limiter,
+	}, nil
+}
+
+// server returns the next resolver in the pool, round-robin.
+func (p *resolverPool) server() string {
+	idx := atomic.AddUint32(&p.next, 1)
+	return p.servers[int(idx)%len(p.servers)]
+}
+
+// exchange sends m to a resolver from the pool, retrying against the next
+// resolver on timeout or SERVFAIL.
+func (p *resolverPool) exchange(m *dns.Msg) (*dns.Msg, error) {
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if p.limiter != nil {
+			if err := p.limiter.Wait(context.Background()); err != nil {
+				return nil, err
+			}
+		}
+		server := p.server()
+		in, err := p.transport.exchange(m, server)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if in.Rcode == dns.RcodeServerFailure {
+			lastErr = fmt.Errorf("resolver %s returned SERVFAIL", server)
+			continue
+		}
+		return in, nil
+	}
+	return nil, fmt.Errorf("all resolvers failed: %w", lastErr)
+}
+
+// probe sends a simple A query for domain to every resolver in the pool and
+// returns the subset that answered successfully.
+func (p *resolverPool) probe(domain string) []string {
+	alive := make([]string, 0, len(p.servers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, server := range p.servers {
+		server := server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m := new(dns.Msg)
+			m.SetQuestion(dns.Fqdn(domain), dns.TypeA)
+			if _, err := p.transport.exchange(m, server); err != nil {
+				return
+			}
+			mu.Lock()
+			alive = append(alive, server)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return alive
+}
+
+func (p *resolverPool) setServers(servers []string) {
+	p.servers = servers
+}
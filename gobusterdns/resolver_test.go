@@ -0,0 +1,79 @@
+package gobusterdns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// stubTransport is a dnsTransport backed by a plain function, so resolver
+// pool behaviour (round-robin, retries) can be tested without touching the
+// network.
+type stubTransport struct {
+	exchangeFunc func(m *dns.Msg, server string) (*dns.Msg, error)
+}
+
+func (t *stubTransport) exchange(m *dns.Msg, server string) (*dns.Msg, error) {
+	return t.exchangeFunc(m, server)
+}
+
+func TestResolverPoolServerRoundRobin(t *testing.T) {
+	p := &resolverPool{servers: []string{"a", "b", "c"}}
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		got = append(got, p.server())
+	}
+
+	want := []string{"b", "c", "a", "b", "c", "a"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("server() sequence = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestResolverPoolExchangeRetriesOnServfail(t *testing.T) {
+	calls := 0
+	p := &resolverPool{
+		// server() starts at index 1, so "servfail" is picked first.
+		servers: []string{"good", "servfail"},
+		retries: 1,
+		transport: &stubTransport{exchangeFunc: func(m *dns.Msg, server string) (*dns.Msg, error) {
+			calls++
+			in := new(dns.Msg)
+			if server == "servfail" {
+				in.Rcode = dns.RcodeServerFailure
+				return in, nil
+			}
+			in.Rcode = dns.RcodeSuccess
+			return in, nil
+		}},
+	}
+
+	in, err := p.exchange(new(dns.Msg))
+	if err != nil {
+		t.Fatalf("exchange returned error: %v", err)
+	}
+	if in.Rcode != dns.RcodeSuccess {
+		t.Fatalf("exchange returned Rcode %d, want RcodeSuccess", in.Rcode)
+	}
+	if calls != 2 {
+		t.Fatalf("transport called %d times, want 2 (1 SERVFAIL + 1 retry)", calls)
+	}
+}
+
+func TestResolverPoolExchangeAllFail(t *testing.T) {
+	p := &resolverPool{
+		servers: []string{"a", "b"},
+		retries: 1,
+		transport: &stubTransport{exchangeFunc: func(m *dns.Msg, server string) (*dns.Msg, error) {
+			return nil, fmt.Errorf("timeout talking to %s", server)
+		}},
+	}
+
+	if _, err := p.exchange(new(dns.Msg)); err == nil {
+		t.Fatal("exchange returned nil error, want an error once every resolver fails")
+	}
+}
@@ -0,0 +1,89 @@
+package gobusterdns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// dnsTransport abstracts how a query is actually put on the wire, so
+// resolverPool can round-robin/retry the same way regardless of whether
+// it's talking plain UDP/TCP, DNS-over-TLS or DNS-over-HTTPS.
+type dnsTransport interface {
+	exchange(m *dns.Msg, server string) (*dns.Msg, error)
+}
+
+// classicTransport is plain UDP (the miekg/dns client falls back to TCP on
+// truncation automatically) or, when useTLS is set, DNS-over-TLS on 853.
+type classicTransport struct {
+	client *dns.Client
+}
+
+func newClassicTransport(useTLS bool, timeout time.Duration) *classicTransport {
+	net := "udp"
+	if useTLS {
+		net = "tcp-tls"
+	}
+	return &classicTransport{client: &dns.Client{Net: net, Timeout: timeout}}
+}
+
+func (t *classicTransport) exchange(m *dns.Msg, server string) (*dns.Msg, error) {
+	in, _, err := t.client.Exchange(m, server)
+	if err == nil && in.Truncated && t.client.Net == "udp" {
+		tcp := &dns.Client{Net: "tcp", Timeout: t.client.Timeout}
+		in, _, err = tcp.Exchange(m, server)
+	}
+	return in, err
+}
+
+// dohTransport implements DNS-over-HTTPS (RFC 8484): the query is packed
+// into an application/dns-message POST body and the response unpacked the
+// same way.
+type dohTransport struct {
+	httpClient *http.Client
+}
+
+func newDoHTransport(timeout time.Duration) *dohTransport {
+	return &dohTransport{httpClient: &http.Client{Timeout: timeout}}
+}
+
+// exchange POSTs m to server, which must be the full DoH query URL (e.g.
+// https://cloudflare-dns.com/dns-query).
+func (t *dohTransport) exchange(m *dns.Msg, server string) (*dns.Msg, error) {
+	packed, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server %s returned status %d", server, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := new(dns.Msg)
+	if err := in.Unpack(body); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
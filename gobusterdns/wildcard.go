@@ -0,0 +1,122 @@
+package gobusterdns
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/miekg/dns"
+)
+
+// defaultWildcardProbes is how many random subdomains are resolved during
+// PreRun to fingerprint the wildcard response(s) for the target domain.
+const defaultWildcardProbes = 5
+
+// maxCnameChainDepth bounds how many CNAME hops fingerprintChain will
+// follow before giving up, so a misbehaving nameserver can't hang PreRun.
+const maxCnameChainDepth = 10
+
+// detectWildcard probes the target domain with a handful of random,
+// UUID-prefixed subdomains and records the fingerprint of every distinct
+// response seen, so Run can later drop candidates whose response matches
+// one of them.
+func (d *GobusterDNS) detectWildcard() error {
+	probes := d.options.WildcardProbes
+	if probes <= 0 {
+		probes = defaultWildcardProbes
+	}
+
+	for i := 0; i < probes; i++ {
+		guid := uuid.New()
+		fp, err := d.fingerprintChain(fmt.Sprintf("%s.%s", guid, d.options.Domain))
+		if err != nil || fp == "" {
+			continue
+		}
+		d.isWildcard = true
+		d.wildcardFingerprints[fp] = struct{}{}
+	}
+
+	if d.isWildcard {
+		log.Printf("[-] Wildcard DNS found. %d distinct response fingerprint(s)", len(d.wildcardFingerprints))
+		if !d.options.WildcardForced {
+			return fmt.Errorf("To force processing of Wildcard DNS, specify the '--wildcard' switch.")
+		}
+	}
+
+	return nil
+}
+
+// isWildcardResponse reports whether fp matches a fingerprint recorded by
+// detectWildcard.
+func (d *GobusterDNS) isWildcardResponse(fp string) bool {
+	if !d.isWildcard {
+		return false
+	}
+	_, known := d.wildcardFingerprints[fp]
+	return known
+}
+
+// fingerprintChain resolves name with an A query, following any CNAME chain
+// to its terminal target, and returns a stable hash of every RR seen along
+// the way. Only the record type and rdata are hashed, never the owner name,
+// since that differs on every probe; this also means a wildcard that CNAMEs
+// to a per-request unique host is still caught via the terminal records
+// that chain eventually resolves to.
+func (d *GobusterDNS) fingerprintChain(name string) (string, error) {
+	var rrs []dns.RR
+
+	next := name
+	for depth := 0; depth < maxCnameChainDepth; depth++ {
+		m := new(dns.Msg)
+		m.SetQuestion(dns.Fqdn(next), dns.TypeA)
+		in, err := d.resolvers.exchange(m)
+		if err != nil {
+			break
+		}
+
+		rrs = append(rrs, in.Answer...)
+
+		cname := ""
+		hasAddress := false
+		for _, rr := range in.Answer {
+			switch v := rr.(type) {
+			case *dns.CNAME:
+				cname = v.Target
+			case *dns.A, *dns.AAAA:
+				hasAddress = true
+			}
+		}
+
+		if hasAddress || cname == "" {
+			break
+		}
+		next = cname
+	}
+
+	return hashRecords(rrs), nil
+}
+
+// hashRecords hashes the sorted "TYPE value" lines of rrs, so an equivalent
+// answer set always produces the same fingerprint regardless of RR order.
+func hashRecords(rrs []dns.RR) string {
+	if len(rrs) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rrs))
+	for _, rr := range rrs {
+		value := rrValue(rr)
+		if value == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", dns.TypeToString[rr.Header().Rrtype], value))
+	}
+	sort.Strings(lines)
+
+	h := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,82 @@
+package gobusterdns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func mustParseIP(t *testing.T, s string) net.IP {
+	t.Helper()
+	ip := net.ParseIP(s)
+	if ip == nil {
+		t.Fatalf("invalid IP %q", s)
+	}
+	return ip
+}
+
+func TestHashRecordsOrderIndependent(t *testing.T) {
+	a := &dns.A{A: mustParseIP(t, "1.2.3.4")}
+	aaaa := &dns.AAAA{AAAA: mustParseIP(t, "::1")}
+	a.Hdr.Rrtype = dns.TypeA
+	aaaa.Hdr.Rrtype = dns.TypeAAAA
+
+	h1 := hashRecords([]dns.RR{a, aaaa})
+	h2 := hashRecords([]dns.RR{aaaa, a})
+
+	if h1 != h2 {
+		t.Errorf("hashRecords is order-dependent: %q != %q", h1, h2)
+	}
+}
+
+func TestHashRecordsContentSensitive(t *testing.T) {
+	a1 := &dns.A{A: mustParseIP(t, "1.2.3.4")}
+	a1.Hdr.Rrtype = dns.TypeA
+	a2 := &dns.A{A: mustParseIP(t, "4.3.2.1")}
+	a2.Hdr.Rrtype = dns.TypeA
+
+	if hashRecords([]dns.RR{a1}) == hashRecords([]dns.RR{a2}) {
+		t.Error("hashRecords produced the same fingerprint for different record sets")
+	}
+}
+
+func TestHashRecordsEmpty(t *testing.T) {
+	if got := hashRecords(nil); got != "" {
+		t.Errorf("hashRecords(nil) = %q, want empty string", got)
+	}
+}
+
+func TestFingerprintChainFollowsCname(t *testing.T) {
+	target := &dns.A{A: mustParseIP(t, "93.184.216.34")}
+	target.Hdr.Rrtype = dns.TypeA
+
+	cname := &dns.CNAME{Target: "terminal.example.com."}
+	cname.Hdr.Rrtype = dns.TypeCNAME
+
+	d := &GobusterDNS{
+		resolvers: &resolverPool{
+			servers: []string{"stub"},
+			transport: &stubTransport{exchangeFunc: func(m *dns.Msg, server string) (*dns.Msg, error) {
+				in := new(dns.Msg)
+				q := m.Question[0].Name
+				if q == dns.Fqdn("probe.example.com") {
+					in.Answer = []dns.RR{cname}
+				} else {
+					in.Answer = []dns.RR{target}
+				}
+				return in, nil
+			}},
+		},
+	}
+
+	fp, err := d.fingerprintChain("probe.example.com")
+	if err != nil {
+		t.Fatalf("fingerprintChain returned error: %v", err)
+	}
+
+	want := hashRecords([]dns.RR{cname, target})
+	if fp != want {
+		t.Errorf("fingerprintChain() = %q, want %q", fp, want)
+	}
+}